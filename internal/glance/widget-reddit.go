@@ -13,8 +13,12 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/valyala/fastjson"
 )
 
 var (
@@ -25,16 +29,24 @@ var (
 type redditWidget struct {
 	logger              *slog.Logger
 	widgetBase          `yaml:",inline"`
-	redditAccessToken   string
-	redditAppName       string            `yaml:"reddit-app-name"`
-	redditClientID      string            `yaml:"reddit-client-id"`
-	redditClientSecret  string            `yaml:"reddit-client-secret"`
-	Posts               forumPostList     `yaml:"-"`
+	tokenSource         *redditTokenSource
+	rateLimit           *redditRateLimit
+	redditAppName       string        `yaml:"reddit-app-name"`
+	redditClientID      string        `yaml:"reddit-client-id"`
+	redditClientSecret  string        `yaml:"reddit-client-secret"`
+	Posts               forumPostList `yaml:"-"`
+	source              redditSource
 	Subreddit           string            `yaml:"subreddit"`
+	Subreddits          []string          `yaml:"subreddits"`
+	Multireddit         string            `yaml:"multireddit"`
+	Username            string            `yaml:"username"`
+	IncludeComments     bool              `yaml:"include-comments"`
 	Proxy               proxyOptionsField `yaml:"proxy"`
 	Style               string            `yaml:"style"`
 	ShowThumbnails      bool              `yaml:"show-thumbnails"`
 	ShowFlairs          bool              `yaml:"show-flairs"`
+	Nsfw                string            `yaml:"nsfw"`
+	NsfwThumbnails      bool              `yaml:"nsfw-thumbnails"`
 	SortBy              string            `yaml:"sort-by"`
 	TopPeriod           string            `yaml:"top-period"`
 	Search              string            `yaml:"search"`
@@ -52,69 +64,275 @@ type redditTokenResponse struct {
 	Scope       string `json:"scope"`
 }
 
-func (widget *redditWidget) fetchRedditAccessToken() error {
-	// Only execute if a matching configuration is provider
-	if widget.redditAppName == "" || widget.redditClientID == "" || widget.redditClientSecret == "" {
-		return nil
+// redditTokenSource caches an OAuth client-credentials access token for a
+// single set of Reddit app credentials and transparently refreshes it
+// shortly before it expires. Widgets configured with the same
+// reddit-client-id share a source (see getRedditTokenSource) so that N
+// reddit widgets on one page don't each fetch their own token.
+type redditTokenSource struct {
+	appName      string
+	clientID     string
+	clientSecret string
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// redditTokenExpiryBuffer is how much life a cached token must have left
+// before we'll hand it out rather than refreshing it first.
+const redditTokenExpiryBuffer = 60 * time.Second
+
+var (
+	redditTokenSourcesMu sync.Mutex
+	redditTokenSources   = make(map[string]*redditTokenSource)
+)
+
+// getRedditTokenSource returns the token source for the given credentials,
+// registering a new one on first use.
+func getRedditTokenSource(appName, clientID, clientSecret string) *redditTokenSource {
+	redditTokenSourcesMu.Lock()
+	defer redditTokenSourcesMu.Unlock()
+
+	if source, ok := redditTokenSources[clientID]; ok {
+		return source
+	}
+
+	source := &redditTokenSource{
+		appName:      appName,
+		clientID:     clientID,
+		clientSecret: clientSecret,
+	}
+	redditTokenSources[clientID] = source
+
+	return source
+}
+
+// Token returns a cached access token that still has more than
+// redditTokenExpiryBuffer left on its lifetime, fetching a new one from
+// Reddit if it doesn't.
+func (t *redditTokenSource) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.token != "" && time.Until(t.expiresAt) > redditTokenExpiryBuffer {
+		return t.token, nil
 	}
 
-	widget.logger.Info("Found reddit API credentials", "app-name", widget.redditAppName, "client-id", widget.redditClientID, "client-secret", widget.redditClientSecret)
+	return t.refreshLocked(ctx)
+}
+
+// Invalidate drops the cached token so that the next call to Token fetches
+// a fresh one. Used after Reddit rejects a token as expired or revoked
+// ahead of schedule.
+func (t *redditTokenSource) Invalidate() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.token = ""
+}
 
-	auth := base64.StdEncoding.EncodeToString([]byte(widget.redditClientID + ":" + widget.redditClientSecret))
+func (t *redditTokenSource) refreshLocked(ctx context.Context) (string, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(t.clientID + ":" + t.clientSecret))
 
-	// Prepare form data
 	data := url.Values{}
 	data.Set("grant_type", "client_credentials")
 
-	// Create request
-	req, err := http.NewRequest("POST", "https://www.reddit.com/api/v1/access_token", strings.NewReader(data.Encode()))
+	request, err := http.NewRequestWithContext(ctx, "POST", "https://www.reddit.com/api/v1/access_token", strings.NewReader(data.Encode()))
 	if err != nil {
-		return err
+		return "", err
 	}
 
-	// Set headers
-	req.Header.Add("Authorization", "Basic "+auth)
-	req.Header.Add("User-Agent", fmt.Sprintf("%s/1.0", widget.redditAppName))
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Authorization", "Basic "+auth)
+	request.Header.Set("User-Agent", fmt.Sprintf("%s/1.0", t.appName))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	// Make the request
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	response, err := defaultHTTPClient.Do(request)
 	if err != nil {
-		return fmt.Errorf("querying Reddit API: %w", err)
+		return "", fmt.Errorf("querying Reddit API: %w", err)
 	}
-	defer resp.Body.Close()
+	defer response.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	body, err := io.ReadAll(response.Body)
 	if err != nil {
-		return fmt.Errorf("reading response body: %w", err)
+		return "", fmt.Errorf("reading response body: %w", err)
 	}
 
-	// Check for error status code
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("API request failed with status %d", response.StatusCode)
 	}
 
-	// Parse JSON response
 	var tokenResp redditTokenResponse
-	err = json.Unmarshal(body, &tokenResp)
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", fmt.Errorf("unmarshalling Reddit API response: %w", err)
+	}
+
+	t.token = tokenResp.AccessToken
+	t.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+
+	// Intentionally not logging the token itself, even at debug level.
+	slog.Debug("Fetched Reddit access token", "app-name", t.appName)
+
+	return t.token, nil
+}
+
+// redditRateLimit tracks the x-ratelimit-* headers Reddit returns on every
+// API response for a single set of credentials (or, when unauthenticated,
+// for this instance's outgoing IP) so that a burst of requests across
+// several reddit widgets backs off before Reddit starts returning 429s.
+type redditRateLimit struct {
+	mu        sync.Mutex
+	remaining float64
+	resetAt   time.Time
+}
+
+// redditRateLimitBuffer is how many requests we insist on keeping in
+// reserve before we start waiting out the rest of the window ourselves.
+const redditRateLimitBuffer = 5
+
+var (
+	redditRateLimitsMu sync.Mutex
+	redditRateLimits   = make(map[string]*redditRateLimit)
+)
+
+// getRedditRateLimit returns the rate limit tracker for the given key,
+// registering a new one on first use. The key is the reddit-client-id for
+// authenticated widgets, or a shared constant for unauthenticated ones.
+func getRedditRateLimit(key string) *redditRateLimit {
+	redditRateLimitsMu.Lock()
+	defer redditRateLimitsMu.Unlock()
+
+	if limit, ok := redditRateLimits[key]; ok {
+		return limit
+	}
+
+	limit := &redditRateLimit{}
+	redditRateLimits[key] = limit
+
+	return limit
+}
+
+func (r *redditRateLimit) observe(header http.Header) {
+	remaining, err := strconv.ParseFloat(header.Get("x-ratelimit-remaining"), 64)
 	if err != nil {
-		return fmt.Errorf("unmarshalling Reddit API response: %w", err)
+		return
 	}
 
-	widget.redditAccessToken = tokenResp.AccessToken
+	resetSeconds, err := strconv.Atoi(header.Get("x-ratelimit-reset"))
+	if err != nil {
+		return
+	}
 
-	widget.logger.Info("Successfully fetched Reddit access token", "access-token", tokenResp.AccessToken)
+	r.mu.Lock()
+	defer r.mu.Unlock()
 
-	return nil
+	r.remaining = remaining
+	r.resetAt = time.Now().Add(time.Duration(resetSeconds) * time.Second)
+}
+
+// waitIfNearLimit blocks until Reddit's rate limit window resets if the
+// last response we saw indicated we're down to our last few requests.
+func (r *redditRateLimit) waitIfNearLimit(ctx context.Context) error {
+	r.mu.Lock()
+	remaining, resetAt := r.remaining, r.resetAt
+	r.mu.Unlock()
+
+	if remaining > redditRateLimitBuffer || !time.Now().Before(resetAt) {
+		return nil
+	}
+
+	select {
+	case <-time.After(time.Until(resetAt)):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// redditSource describes where a reddit widget's posts come from: a single
+// subreddit, several combined into one listing, a user's multireddit, or a
+// user's own submissions.
+type redditSource struct {
+	path        string // URL path segment, e.g. "r/golang+rust" or "user/foo/m/bar"
+	displayName string // human-readable label, e.g. "r/golang+rust"
+	combined    bool   // true when a post's originating subreddit should be tagged
+	isUser      bool   // true for a user's submissions, where sort is a query param rather than a path segment
+	username    string // set alongside isUser, used to also reach the user's comments listing
+}
+
+// resolveRedditSource turns the widget's subreddit/subreddits/multireddit/
+// username configuration into a single redditSource, rejecting
+// configurations that specify more than one of them.
+func resolveRedditSource(subreddit string, subreddits []string, multireddit, username, search string) (redditSource, error) {
+	set := 0
+	if subreddit != "" {
+		set++
+	}
+	if len(subreddits) > 0 {
+		set++
+	}
+	if multireddit != "" {
+		set++
+	}
+	if username != "" {
+		set++
+	}
+
+	if set == 0 {
+		return redditSource{}, errors.New("one of `subreddit`, `subreddits`, `multireddit` or `username` is required")
+	}
+
+	if set > 1 {
+		return redditSource{}, errors.New("only one of `subreddit`, `subreddits`, `multireddit` or `username` may be specified")
+	}
+
+	// Reddit's search only accepts a single `subreddit:` qualifier, which
+	// `subreddits` with more than one entry and `multireddit` can't provide,
+	// so reject the combination up front rather than silently dropping `search`.
+	if search != "" && (len(subreddits) > 1 || multireddit != "") {
+		return redditSource{}, errors.New("`search` cannot be used with `multireddit` or more than one entry in `subreddits`")
+	}
+
+	if username != "" {
+		return redditSource{
+			path:        "user/" + username + "/submitted",
+			displayName: "u/" + username,
+			isUser:      true,
+			username:    username,
+		}, nil
+	}
+
+	if multireddit != "" {
+		parts := strings.Split(multireddit, "/")
+
+		if len(parts) != 4 || parts[0] != "user" || parts[2] != "m" {
+			return redditSource{}, fmt.Errorf("invalid `multireddit` value %q, expected `user/{name}/m/{multi}`", multireddit)
+		}
+
+		return redditSource{path: multireddit, displayName: "m/" + parts[3]}, nil
+	}
+
+	if len(subreddits) > 0 {
+		combined := strings.Join(subreddits, "+")
+
+		return redditSource{
+			path:        "r/" + combined,
+			displayName: "r/" + combined,
+			combined:    len(subreddits) > 1,
+		}, nil
+	}
+
+	return redditSource{path: "r/" + subreddit, displayName: "r/" + subreddit}, nil
 }
 
 func (widget *redditWidget) initialize() error {
-	if widget.Subreddit == "" {
-		return errors.New("subreddit is required")
+	source, err := resolveRedditSource(widget.Subreddit, widget.Subreddits, widget.Multireddit, widget.Username, widget.Search)
+	if err != nil {
+		return err
 	}
 
+	widget.source = source
+
 	if widget.Limit <= 0 {
 		widget.Limit = 15
 	}
@@ -131,6 +349,10 @@ func (widget *redditWidget) initialize() error {
 		widget.TopPeriod = "day"
 	}
 
+	if !isValidRedditNsfwMode(widget.Nsfw) {
+		widget.Nsfw = "hide"
+	}
+
 	if widget.RequestUrlTemplate != "" {
 		if !strings.Contains(widget.RequestUrlTemplate, "{REQUEST-URL}") {
 			return errors.New("no `{REQUEST-URL}` placeholder specified")
@@ -139,13 +361,35 @@ func (widget *redditWidget) initialize() error {
 
 	widget.logger = slog.New(slog.NewTextHandler(os.Stdout, nil))
 
-	if err := widget.fetchRedditAccessToken(); err != nil {
-		return fmt.Errorf("fetching Reddit API access token: %w", err)
+	rateLimitKey := "anonymous"
+
+	if widget.redditAppName != "" && widget.redditClientID != "" && widget.redditClientSecret != "" {
+		widget.tokenSource = getRedditTokenSource(widget.redditAppName, widget.redditClientID, widget.redditClientSecret)
+		rateLimitKey = widget.redditClientID
+
+		if _, err := widget.tokenSource.Token(context.Background()); err != nil {
+			return fmt.Errorf("fetching Reddit API access token: %w", err)
+		}
+	}
+
+	widget.rateLimit = getRedditRateLimit(rateLimitKey)
+
+	var client requestDoer = defaultHTTPClient
+	if widget.Proxy.client != nil {
+		client = widget.Proxy.client
+	}
+
+	// The about.json pre-flight check only makes sense for a single subreddit;
+	// a combination or a multireddit doesn't have one dedicated about page.
+	if widget.Subreddit != "" {
+		if err := checkSubredditAccess(context.Background(), client, widget.Subreddit, widget.redditAppName, widget.tokenSource, widget.rateLimit); err != nil {
+			return fmt.Errorf("checking subreddit: %w", err)
+		}
 	}
 
 	widget.
-		withTitle("r/" + widget.Subreddit).
-		withTitleURL("https://www.reddit.com/r/" + widget.Subreddit + "/").
+		withTitle(widget.source.displayName).
+		withTitleURL("https://www.reddit.com/" + widget.source.path + "/").
 		withCacheDuration(30 * time.Minute)
 
 	return nil
@@ -155,7 +399,8 @@ func isValidRedditSortType(sortBy string) bool {
 	return sortBy == "hot" ||
 		sortBy == "new" ||
 		sortBy == "top" ||
-		sortBy == "rising"
+		sortBy == "rising" ||
+		sortBy == "controversial"
 }
 
 func isValidRedditTopPeriod(period string) bool {
@@ -167,10 +412,15 @@ func isValidRedditTopPeriod(period string) bool {
 		period == "all"
 }
 
+func isValidRedditNsfwMode(mode string) bool {
+	return mode == "show" || mode == "hide" || mode == "blur"
+}
+
 func (widget *redditWidget) update(ctx context.Context) {
 	// TODO: refactor, use a struct to pass all of these
 	posts, err := fetchSubredditPosts(
-		widget.Subreddit,
+		ctx,
+		widget.source,
 		widget.SortBy,
 		widget.TopPeriod,
 		widget.Search,
@@ -178,8 +428,12 @@ func (widget *redditWidget) update(ctx context.Context) {
 		widget.RequestUrlTemplate,
 		widget.Proxy.client,
 		widget.ShowFlairs,
+		widget.IncludeComments,
+		widget.Nsfw,
+		widget.NsfwThumbnails,
 		widget.redditAppName,
-		widget.redditAccessToken,
+		widget.tokenSource,
+		widget.rateLimit,
 	)
 
 	if !widget.canContinueUpdateAfterHandlingErr(err) {
@@ -211,33 +465,231 @@ func (widget *redditWidget) Render() template.HTML {
 
 }
 
-type subredditResponseJson struct {
-	Data struct {
-		Children []struct {
-			Data struct {
-				Id            string  `json:"id"`
-				Title         string  `json:"title"`
-				Upvotes       int     `json:"ups"`
-				Url           string  `json:"url"`
-				Time          float64 `json:"created"`
-				CommentsCount int     `json:"num_comments"`
-				Domain        string  `json:"domain"`
-				Permalink     string  `json:"permalink"`
-				Stickied      bool    `json:"stickied"`
-				Pinned        bool    `json:"pinned"`
-				IsSelf        bool    `json:"is_self"`
-				Thumbnail     string  `json:"thumbnail"`
-				Flair         string  `json:"link_flair_text"`
-				ParentList    []struct {
-					Id        string `json:"id"`
-					Subreddit string `json:"subreddit"`
-					Permalink string `json:"permalink"`
-				} `json:"crosspost_parent_list"`
-			} `json:"data"`
-		} `json:"children"`
+// redditJsonParserPool reuses fastjson parsers across calls to
+// decodeRedditListing instead of allocating one per request.
+var redditJsonParserPool = sync.Pool{
+	New: func() any { return &fastjson.Parser{} },
+}
+
+// decodeRedditListing fetches a Reddit listing and walks data.children[*].data
+// with fastjson rather than decoding it into an intermediate struct with
+// encoding/json, since only a handful of the ~80 fields Reddit sends per
+// post are ever used.
+func decodeRedditListing(
+	ctx context.Context,
+	client requestDoer,
+	requestUrl string,
+	redditAppName string,
+	tokenSource *redditTokenSource,
+	rateLimit *redditRateLimit,
+	source redditSource,
+	showFlairs bool,
+	commentsUrlTemplate string,
+	nsfwMode string,
+	nsfwThumbnails bool,
+) (forumPostList, error) {
+	body, err := doRedditRequest(ctx, client, requestUrl, redditAppName, tokenSource, rateLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := redditJsonParserPool.Get().(*fastjson.Parser)
+	defer redditJsonParserPool.Put(parser)
+
+	value, err := parser.ParseBytes(body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Reddit response: %w", err)
+	}
+
+	children := value.GetArray("data", "children")
+	posts := make(forumPostList, 0, len(children))
+
+	for _, child := range children {
+		data := child.Get("data")
+		if data == nil {
+			continue
+		}
+
+		if fp, ok := buildForumPostFromJson(data, source, showFlairs, commentsUrlTemplate, nsfwMode, nsfwThumbnails); ok {
+			posts = append(posts, fp)
+		}
+	}
+
+	return posts, nil
+}
+
+// buildForumPostFromJson converts a single `data.children[*].data` object
+// from a Reddit listing into a forumPost, returning ok=false for
+// stickied/pinned posts, or for NSFW posts when nsfwMode is "hide", that
+// should be skipped entirely.
+func buildForumPostFromJson(data *fastjson.Value, source redditSource, showFlairs bool, commentsUrlTemplate, nsfwMode string, nsfwThumbnails bool) (forumPost, bool) {
+	str := func(key string) string { return string(data.GetStringBytes(key)) }
+
+	if data.GetBool("stickied") || data.GetBool("pinned") {
+		return forumPost{}, false
+	}
+
+	over18 := data.GetBool("over_18")
+	if over18 && nsfwMode == "hide" {
+		return forumPost{}, false
+	}
+
+	id := str("id")
+	permalink := str("permalink")
+	subreddit := str("subreddit")
+
+	var commentsUrl string
+
+	if commentsUrlTemplate == "" {
+		commentsUrl = "https://www.reddit.com" + permalink
+	} else {
+		commentsUrl = templateRedditCommentsURL(commentsUrlTemplate, subreddit, id, permalink)
+	}
+
+	title := str("title")
+	if title == "" {
+		// Comment listings carry the title of the post being commented on here.
+		title = str("link_title")
+	}
+
+	fp := forumPost{
+		Title:           html.UnescapeString(title),
+		DiscussionUrl:   commentsUrl,
+		TargetUrlDomain: str("domain"),
+		CommentCount:    data.GetInt("num_comments"),
+		Score:           data.GetInt("ups"),
+		TimePosted:      time.Unix(int64(data.GetFloat64("created")), 0),
+	}
+
+	if source.isUser && subreddit != "" {
+		fp.TargetUrlDomain = "r/" + subreddit
+	}
+
+	thumbnail := str("thumbnail")
+	if thumbnail != "" && thumbnail != "self" && thumbnail != "default" && thumbnail != "nsfw" &&
+		(!over18 || nsfwThumbnails) {
+		fp.ThumbnailUrl = html.UnescapeString(thumbnail)
+	}
+
+	if !data.GetBool("is_self") {
+		fp.TargetUrl = str("url")
+	}
+
+	flair := str("link_flair_text")
+	if showFlairs && flair != "" {
+		fp.Tags = append(fp.Tags, flair)
+	}
+
+	if source.combined && subreddit != "" {
+		fp.Tags = append(fp.Tags, "r/"+subreddit)
+	}
+
+	// forumPost has no dedicated NSFW field in this tree, so Tags is the only
+	// hook the template has to tell "blur" and "show" apart; "NSFW-blur" is
+	// expected to map to a CSS class that blurs the thumbnail, "NSFW" to a
+	// plain label with no blur applied.
+	if over18 {
+		if nsfwMode == "blur" {
+			fp.Tags = append(fp.Tags, "NSFW-blur")
+		} else {
+			fp.Tags = append(fp.Tags, "NSFW")
+		}
+	}
+
+	if parents := data.GetArray("crosspost_parent_list"); len(parents) > 0 {
+		parent := parents[0]
+		parentSubreddit := string(parent.GetStringBytes("subreddit"))
+		parentId := string(parent.GetStringBytes("id"))
+		parentPermalink := string(parent.GetStringBytes("permalink"))
+
+		fp.IsCrosspost = true
+		fp.TargetUrlDomain = "r/" + parentSubreddit
+
+		if commentsUrlTemplate == "" {
+			fp.TargetUrl = "https://www.reddit.com" + parentPermalink
+		} else {
+			fp.TargetUrl = templateRedditCommentsURL(commentsUrlTemplate, parentSubreddit, parentId, parentPermalink)
+		}
+	}
+
+	return fp, true
+}
+
+var (
+	errSubredditPrivate     = errors.New("this subreddit is private")
+	errSubredditQuarantined = errors.New("this subreddit is quarantined, opt-in required")
+	errSubredditBanned      = errors.New("this subreddit has been banned")
+	errSubredditNotFound    = errors.New("this subreddit does not exist")
+)
+
+type subredditAboutJson struct {
+	Reason string `json:"reason"`
+	Data   struct {
+		Quarantine bool `json:"quarantine"`
 	} `json:"data"`
 }
 
+// checkSubredditAccess makes a pre-flight call to a subreddit's about page
+// so that private, quarantined, banned, or nonexistent subreddits surface a
+// specific, actionable error during initialize() rather than a generic
+// failure on first update.
+func checkSubredditAccess(
+	ctx context.Context,
+	client requestDoer,
+	subreddit string,
+	redditAppName string,
+	tokenSource *redditTokenSource,
+	rateLimit *redditRateLimit,
+) error {
+	baseURL := "https://www.reddit.com"
+	if tokenSource != nil {
+		baseURL = "https://oauth.reddit.com"
+	}
+
+	requestUrl := fmt.Sprintf("%s/r/%s/about.json", baseURL, subreddit)
+
+	body, err := doRedditRequest(ctx, client, requestUrl, redditAppName, tokenSource, rateLimit)
+
+	var statusErr *redditStatusError
+	if errors.As(err, &statusErr) {
+		var about subredditAboutJson
+		_ = json.Unmarshal(statusErr.Body, &about)
+
+		// A banned subreddit still returns 404 with a `reason` field, so the
+		// reason must be checked before falling back to the blanket
+		// status-code mapping, or it's never reachable.
+		switch {
+		case about.Reason == "private":
+			return fmt.Errorf("r/%s: %w", subreddit, errSubredditPrivate)
+		case about.Reason == "quarantined":
+			return fmt.Errorf("r/%s: %w", subreddit, errSubredditQuarantined)
+		case about.Reason == "banned":
+			return fmt.Errorf("r/%s: %w", subreddit, errSubredditBanned)
+		case statusErr.StatusCode == http.StatusNotFound:
+			return fmt.Errorf("r/%s: %w", subreddit, errSubredditNotFound)
+		case statusErr.StatusCode == http.StatusForbidden:
+			return fmt.Errorf("r/%s: %w", subreddit, errSubredditPrivate)
+		}
+
+		return statusErr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var about subredditAboutJson
+	if err := json.Unmarshal(body, &about); err != nil {
+		return fmt.Errorf("unmarshalling subreddit about response: %w", err)
+	}
+
+	if about.Data.Quarantine {
+		return fmt.Errorf("r/%s: %w", subreddit, errSubredditQuarantined)
+	}
+
+	return nil
+}
+
 func templateRedditCommentsURL(template, subreddit, postId, postPath string) string {
 	template = strings.ReplaceAll(template, "{SUBREDDIT}", subreddit)
 	template = strings.ReplaceAll(template, "{POST-ID}", postId)
@@ -246,8 +698,125 @@ func templateRedditCommentsURL(template, subreddit, postId, postPath string) str
 	return template
 }
 
+// redditBackoffSchedule is how long to wait between retries of a 429
+// response when Reddit doesn't give us a Retry-After header to go by.
+var redditBackoffSchedule = []time.Duration{
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2 * time.Second,
+}
+
+// doRedditRequest issues a GET against the Reddit API and returns the raw
+// response body. It waits out the current rate limit window up front if a
+// previous response indicated we're close to exhausting it, retries once
+// with a freshly fetched token if an authenticated request comes back
+// 401 or 403, and retries a bounded number of times with backoff on 429.
+func doRedditRequest(
+	ctx context.Context,
+	client requestDoer,
+	requestUrl string,
+	redditAppName string,
+	tokenSource *redditTokenSource,
+	rateLimit *redditRateLimit,
+) ([]byte, error) {
+	allowAuthRetry := true
+	rateLimitAttempt := 0
+
+	for {
+		if rateLimit != nil {
+			if err := rateLimit.waitIfNearLimit(ctx); err != nil {
+				return nil, err
+			}
+		}
+
+		request, err := http.NewRequestWithContext(ctx, "GET", requestUrl, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		// Required to increase rate limit, otherwise Reddit randomly returns 429 even after just 2 requests
+		if redditAppName == "" {
+			setBrowserUserAgentHeader(request)
+		} else {
+			request.Header.Set("User-Agent", fmt.Sprintf("%s/1.0", redditAppName))
+		}
+
+		if tokenSource != nil {
+			token, err := tokenSource.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("getting Reddit access token: %w", err)
+			}
+
+			request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		}
+
+		response, err := client.Do(request)
+		if err != nil {
+			return nil, fmt.Errorf("sending request to Reddit: %w", err)
+		}
+
+		if rateLimit != nil {
+			rateLimit.observe(response.Header)
+		}
+
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("reading Reddit response: %w", err)
+		}
+
+		if response.StatusCode == http.StatusTooManyRequests {
+			if rateLimitAttempt >= len(redditBackoffSchedule) {
+				return nil, fmt.Errorf("rate limited by Reddit after %d retries", rateLimitAttempt)
+			}
+
+			wait := redditBackoffSchedule[rateLimitAttempt]
+			rateLimitAttempt++
+
+			if retryAfter, err := strconv.Atoi(response.Header.Get("Retry-After")); err == nil {
+				wait = time.Duration(retryAfter) * time.Second
+			}
+
+			select {
+			case <-time.After(wait):
+				continue
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+
+		if response.StatusCode == http.StatusUnauthorized || response.StatusCode == http.StatusForbidden {
+			if allowAuthRetry && tokenSource != nil {
+				allowAuthRetry = false
+				tokenSource.Invalidate()
+				continue
+			}
+		}
+
+		if response.StatusCode != http.StatusOK {
+			return nil, &redditStatusError{StatusCode: response.StatusCode, Body: body}
+		}
+
+		return body, nil
+	}
+}
+
+// redditStatusError carries the HTTP status code and raw response body from
+// a failed Reddit request so callers can tell, for example, a private
+// subreddit apart from a banned one.
+type redditStatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *redditStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %d from Reddit", e.StatusCode)
+}
+
 func fetchSubredditPosts(
-	subreddit,
+	ctx context.Context,
+	source redditSource,
 	sort,
 	topPeriod,
 	search,
@@ -255,14 +824,23 @@ func fetchSubredditPosts(
 	requestUrlTemplate string,
 	proxyClient *http.Client,
 	showFlairs bool,
+	includeComments bool,
+	nsfwMode string,
+	nsfwThumbnails bool,
 	redditAppName string,
-	redditAccessToken string,
+	tokenSource *redditTokenSource,
+	rateLimit *redditRateLimit,
 ) (forumPostList, error) {
 	query := url.Values{}
 	var requestUrl string
 
-	if search != "" {
-		query.Set("q", search+" subreddit:"+subreddit)
+	// The `subreddit:` search qualifier only makes sense for a single
+	// subreddit; source.path is `user/x/m/y` for a multireddit and
+	// `r/a+b+c` for a combined source, neither of which Reddit accepts there.
+	searchable := search != "" && !source.isUser && strings.HasPrefix(source.path, "r/") && !strings.Contains(source.path, "+")
+
+	if searchable {
+		query.Set("q", search+" subreddit:"+strings.TrimPrefix(source.path, "r/"))
 		query.Set("sort", sort)
 	}
 
@@ -270,18 +848,27 @@ func fetchSubredditPosts(
 		query.Set("t", topPeriod)
 	}
 
+	if source.isUser {
+		query.Set("sort", sort)
+	}
+
 	var baseURL string
 
-	if redditAccessToken != "" {
+	if tokenSource != nil {
 		baseURL = "https://oauth.reddit.com"
 	} else {
 		baseURL = "https://www.reddit.com"
 	}
 
-	if search != "" {
+	switch {
+	case searchable:
 		requestUrl = fmt.Sprintf("%s/search.json?%s", baseURL, query.Encode())
-	} else {
-		requestUrl = fmt.Sprintf("%s/r/%s/%s.json?%s", baseURL, subreddit, sort, query.Encode())
+	case source.isUser:
+		// The user listing endpoints take `sort` as a query parameter rather
+		// than as a path segment the way subreddit listings do.
+		requestUrl = fmt.Sprintf("%s/%s.json?%s", baseURL, source.path, query.Encode())
+	default:
+		requestUrl = fmt.Sprintf("%s/%s/%s.json?%s", baseURL, source.path, sort, query.Encode())
 	}
 
 	var client requestDoer = defaultHTTPClient
@@ -292,86 +879,30 @@ func fetchSubredditPosts(
 		client = proxyClient
 	}
 
-	request, err := http.NewRequest("GET", requestUrl, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	// Required to increase rate limit, otherwise Reddit randomly returns 429 even after just 2 requests
-	if redditAppName == "" {
-		setBrowserUserAgentHeader(request)
-	} else {
-		request.Header.Set("User-Agent", fmt.Sprintf("%s/1.0", redditAppName))
-	}
-
-	if redditAccessToken != "" {
-		request.Header.Set("Authorization", fmt.Sprintf("Bearer %s", redditAccessToken))
-	}
-
-	responseJson, err := decodeJsonFromRequest[subredditResponseJson](client, request)
+	posts, err := decodeRedditListing(ctx, client, requestUrl, redditAppName, tokenSource, rateLimit, source, showFlairs, commentsUrlTemplate, nsfwMode, nsfwThumbnails)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(responseJson.Data.Children) == 0 {
-		return nil, fmt.Errorf("no posts found")
-	}
-
-	posts := make(forumPostList, 0, len(responseJson.Data.Children))
-
-	for i := range responseJson.Data.Children {
-		post := &responseJson.Data.Children[i].Data
+	if source.isUser && includeComments {
+		commentsQuery := url.Values{}
+		commentsQuery.Set("sort", sort)
 
-		if post.Stickied || post.Pinned {
-			continue
+		if sort == "top" {
+			commentsQuery.Set("t", topPeriod)
 		}
 
-		var commentsUrl string
+		commentsRequestUrl := fmt.Sprintf("%s/user/%s/comments.json?%s", baseURL, source.username, commentsQuery.Encode())
 
-		if commentsUrlTemplate == "" {
-			commentsUrl = "https://www.reddit.com" + post.Permalink
+		if commentPosts, err := decodeRedditListing(ctx, client, commentsRequestUrl, redditAppName, tokenSource, rateLimit, source, showFlairs, commentsUrlTemplate, nsfwMode, nsfwThumbnails); err == nil {
+			posts = append(posts, commentPosts...)
 		} else {
-			commentsUrl = templateRedditCommentsURL(commentsUrlTemplate, subreddit, post.Id, post.Permalink)
-		}
-
-		forumPost := forumPost{
-			Title:           html.UnescapeString(post.Title),
-			DiscussionUrl:   commentsUrl,
-			TargetUrlDomain: post.Domain,
-			CommentCount:    post.CommentsCount,
-			Score:           post.Upvotes,
-			TimePosted:      time.Unix(int64(post.Time), 0),
-		}
-
-		if post.Thumbnail != "" && post.Thumbnail != "self" && post.Thumbnail != "default" && post.Thumbnail != "nsfw" {
-			forumPost.ThumbnailUrl = html.UnescapeString(post.Thumbnail)
-		}
-
-		if !post.IsSelf {
-			forumPost.TargetUrl = post.Url
-		}
-
-		if showFlairs && post.Flair != "" {
-			forumPost.Tags = append(forumPost.Tags, post.Flair)
-		}
-
-		if len(post.ParentList) > 0 {
-			forumPost.IsCrosspost = true
-			forumPost.TargetUrlDomain = "r/" + post.ParentList[0].Subreddit
-
-			if commentsUrlTemplate == "" {
-				forumPost.TargetUrl = "https://www.reddit.com" + post.ParentList[0].Permalink
-			} else {
-				forumPost.TargetUrl = templateRedditCommentsURL(
-					commentsUrlTemplate,
-					post.ParentList[0].Subreddit,
-					post.ParentList[0].Id,
-					post.ParentList[0].Permalink,
-				)
-			}
+			slog.Debug("Failed fetching Reddit user comments", "user", source.username, "error", err)
 		}
+	}
 
-		posts = append(posts, forumPost)
+	if len(posts) == 0 {
+		return nil, fmt.Errorf("no posts found")
 	}
 
 	return posts, nil